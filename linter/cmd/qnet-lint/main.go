@@ -5,29 +5,11 @@ import (
 	"os"
 	"os/exec"
 
+	"github.com/QW1CKS/qnet/linter/pkg/sarif"
+	"github.com/QW1CKS/qnet/linter/pkg/sbom"
 	"github.com/QW1CKS/qnet/linter/pkg/validator"
 	"github.com/spf13/cobra"
 )
-	Use:   "sbom [path]",
-	Short: "Generate SBOM for QNet implementation",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		path := args[0]
-		fmt.Printf("Generating SBOM for: %s\n", path)
-
-		// Use syft to generate SBOM
-		sbomPath := "sbom.json"
-		cmd := exec.Command("syft", path, "-o", "json", "--file", sbomPath)
-		if err := cmd.Run(); err != nil {
-			fmt.Printf("Error generating SBOM: %v\n", err)
-			os.Exit(1)
-		}
-
-		fmt.Printf("SBOM generated at: %s\n", sbomPath)
-	},
-}b.com/QW1CKS/qnet/linter/pkg/validator"
-	"github.com/spf13/cobra"
-)
 
 var rootCmd = &cobra.Command{
 	Use:   "qnet-lint",
@@ -36,41 +18,123 @@ var rootCmd = &cobra.Command{
 It checks for compliance in L2 framing, TemplateID, KEY_UPDATE, and BN-Ticket headers.`,
 }
 
+var validateFormat string
+
 var validateCmd = &cobra.Command{
 	Use:   "validate [path]",
 	Short: "Validate a QNet implementation",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		path := args[0]
-		fmt.Printf("Validating QNet implementation at: %s\n", path)
 
-		errors := validator.Validate(path)
-		if len(errors) > 0 {
-			fmt.Println("Validation failed:")
-			for _, err := range errors {
-				fmt.Printf("  - %v\n", err)
+		findings, err := validator.Validate(path)
+		if err != nil {
+			fmt.Printf("validation error: %v\n", err)
+			os.Exit(2)
+		}
+
+		switch validateFormat {
+		case "sarif":
+			log := sarif.FromFindings(findings)
+			out, err := sarif.Marshal(log)
+			if err != nil {
+				fmt.Printf("error rendering SARIF: %v\n", err)
+				os.Exit(2)
 			}
-			os.Exit(1)
+			fmt.Println(string(out))
+		case "json":
+			out, err := validator.ToJSON(findings)
+			if err != nil {
+				fmt.Printf("error rendering findings: %v\n", err)
+				os.Exit(2)
+			}
+			fmt.Println(string(out))
+		case "text", "":
+			fmt.Printf("Validating QNet implementation at: %s\n", path)
+			if len(findings) > 0 {
+				fmt.Println("Validation failed:")
+				for _, f := range findings {
+					fmt.Printf("  - [%s] %s:%d: %s\n", f.Rule, f.File, f.Line, f.Message)
+				}
+			} else {
+				fmt.Println("All validations passed!")
+			}
+		default:
+			fmt.Printf("unknown --format %q (want text, json, or sarif)\n", validateFormat)
+			os.Exit(2)
 		}
 
-		fmt.Println("All validations passed!")
+		if len(findings) > 0 {
+			os.Exit(1)
+		}
 	},
 }
 
+var (
+	sbomFormat string
+	sbomOutput string
+)
+
 var sbomCmd = &cobra.Command{
 	Use:   "sbom [path]",
 	Short: "Generate SBOM for QNet implementation",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		path := args[0]
-		fmt.Printf("Generating SBOM for: %s\n", path)
 
-		// TODO: Integrate syft for SBOM generation
-		fmt.Println("SBOM generation not yet implemented")
+		if sbomFormat == "syft" {
+			runSyft(path, sbomOutput)
+			return
+		}
+
+		doc, err := sbom.Generate(path)
+		if err != nil {
+			fmt.Printf("Error generating SBOM: %v\n", err)
+			os.Exit(1)
+		}
+
+		var out []byte
+		switch sbomFormat {
+		case "cyclonedx", "":
+			out, err = sbom.ToCycloneDX(doc)
+		case "spdx":
+			out, err = sbom.ToSPDX(doc)
+		default:
+			fmt.Printf("unknown --format %q (want cyclonedx, spdx, or syft)\n", sbomFormat)
+			os.Exit(2)
+			return
+		}
+		if err != nil {
+			fmt.Printf("Error rendering SBOM: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := os.WriteFile(sbomOutput, out, 0644); err != nil {
+			fmt.Printf("Error writing %s: %v\n", sbomOutput, err)
+			os.Exit(1)
+		}
+		fmt.Printf("SBOM generated at: %s\n", sbomOutput)
 	},
 }
 
+// runSyft shells out to syft, kept around as an opt-in fallback for
+// ecosystems the native generator doesn't understand yet.
+func runSyft(path, output string) {
+	fmt.Printf("Generating SBOM for: %s (via syft)\n", path)
+	sbomGen := exec.Command("syft", path, "-o", "json", "--file", output)
+	if err := sbomGen.Run(); err != nil {
+		fmt.Printf("Error generating SBOM: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("SBOM generated at: %s\n", output)
+}
+
 func init() {
+	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "output format: text, json, or sarif")
+
+	sbomCmd.Flags().StringVar(&sbomFormat, "format", "cyclonedx", "output format: cyclonedx, spdx, or syft")
+	sbomCmd.Flags().StringVar(&sbomOutput, "output", "sbom.json", "path to write the SBOM to")
+
 	rootCmd.AddCommand(validateCmd)
 	rootCmd.AddCommand(sbomCmd)
 }
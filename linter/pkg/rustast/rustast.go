@@ -0,0 +1,116 @@
+// Package rustast provides a thin client over a small external helper binary
+// that parses Rust source with `syn` and emits a JSON AST summary. Validator
+// rules reason over this summary instead of grepping raw source text, so a
+// comment or a string literal can no longer masquerade as real code.
+package rustast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// HelperBinEnv names the environment variable used to locate the helper
+// binary. When unset, PATH is searched for DefaultHelperBin.
+const HelperBinEnv = "QNET_RUSTAST_HELPER"
+
+// DefaultHelperBin is the name of the helper binary on PATH.
+const DefaultHelperBin = "qnet-rustast"
+
+// Call is a function/method call expression found inside a function body.
+// Name is the last path segment (e.g. "seal" for both `seal(...)` and
+// `aead::seal(...)` / `cipher.seal(...)`), which is all the validator rules
+// need to recognize an AEAD operation regardless of how it's qualified.
+type Call struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+	Col  int    `json:"col"`
+}
+
+// IfExpr is an `if`/`else if` condition found inside a function body, kept in
+// source order so callers can ask "is this the first guard in the function?"
+// to approximate "this check dominates the rest of the body".
+type IfExpr struct {
+	Condition string `json:"condition"`
+	Line      int    `json:"line"`
+	Col       int    `json:"col"`
+}
+
+// Function is one `fn` item, optionally nested in an `impl <ImplType>` block.
+type Function struct {
+	Name      string   `json:"name"`
+	ImplType  string   `json:"impl_type,omitempty"`
+	StartLine int      `json:"start_line"`
+	EndLine   int      `json:"end_line"`
+	Calls     []Call   `json:"calls"`
+	Ifs       []IfExpr `json:"ifs"`
+}
+
+// File is the parsed summary of a single .rs file.
+type File struct {
+	Path      string     `json:"-"`
+	Functions []Function `json:"functions"`
+}
+
+// FirstIf returns the earliest if-condition in the function, or nil if the
+// function has none. A guard appearing here is the closest AST-level proxy
+// we have for "this check runs before anything else in the function".
+func (f Function) FirstIf() *IfExpr {
+	if len(f.Ifs) == 0 {
+		return nil
+	}
+	first := f.Ifs[0]
+	for _, ie := range f.Ifs[1:] {
+		if ie.Line < first.Line {
+			first = ie
+		}
+	}
+	return &first
+}
+
+// HasCall reports whether the function calls something whose last path
+// segment equals name.
+func (f Function) HasCall(name string) bool {
+	for _, c := range f.Calls {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse runs the helper binary against the Rust source at path and returns
+// its AST summary.
+func Parse(path string) (*File, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	bin := os.Getenv(HelperBinEnv)
+	if bin == "" {
+		bin = DefaultHelperBin
+	}
+	binPath, err := exec.LookPath(bin)
+	if err != nil {
+		return nil, fmt.Errorf("rustast: helper binary %q not found on PATH (set %s to override): %w", bin, HelperBinEnv, err)
+	}
+
+	cmd := exec.Command(binPath)
+	cmd.Stdin = bytes.NewReader(src)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("rustast: parsing %s: %w: %s", path, err, stderr.String())
+	}
+
+	var file File
+	if err := json.Unmarshal(stdout.Bytes(), &file); err != nil {
+		return nil, fmt.Errorf("rustast: decoding AST for %s: %w", path, err)
+	}
+	file.Path = path
+	return &file, nil
+}
@@ -0,0 +1,175 @@
+// Package sarif serializes validator.Finding slices as SARIF 2.1.0
+// (https://docs.oasis-open.org/sarif/sarif/v2.1.0/) so QNet compliance
+// findings can be uploaded to GitHub code scanning, GitLab, or any other
+// SARIF-consuming dashboard.
+package sarif
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/QW1CKS/qnet/linter/pkg/validator"
+)
+
+const schemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+const version = "2.1.0"
+const toolName = "qnet-lint"
+const toolInfoURI = "https://github.com/QW1CKS/qnet"
+
+// Log is the SARIF top-level log object.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is a single SARIF run, one per qnet-lint invocation.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analysis tool and the rules it knows about.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is the SARIF toolComponent for qnet-lint itself.
+type Driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+	Version        string `json:"version"`
+	Rules          []Rule `json:"rules"`
+}
+
+// Rule is a SARIF reportingDescriptor for one qnet-lint rule.
+type Rule struct {
+	ID               string          `json:"id"`
+	ShortDescription MultiformatText `json:"shortDescription"`
+	HelpURI          string          `json:"helpUri,omitempty"`
+}
+
+// MultiformatText is SARIF's {"text": "..."} wrapper.
+type MultiformatText struct {
+	Text string `json:"text"`
+}
+
+// Result is a single SARIF result, one per validator.Finding.
+type Result struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   MultiformatText `json:"message"`
+	Locations []Location      `json:"locations"`
+}
+
+// Location pins a Result to a file and region.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is the artifact + region a Result points at.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           Region           `json:"region"`
+}
+
+// ArtifactLocation names the file a Result is in.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is the line/column span a Result covers.
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// toolVersion is set at build time; it defaults to "dev" for local builds.
+var toolVersion = "dev"
+
+// FromFindings builds a SARIF Log for a single qnet-lint run.
+// tool.driver.rules is populated from the distinct fine-grained
+// validator.Finding.Rule values actually present in findings (described via
+// validator.RuleDescriptions), so every results[].ruleId resolves to a rule
+// the driver describes, rather than the coarser ValidationRule the finding
+// came from.
+func FromFindings(findings []validator.Finding) *Log {
+	seen := map[string]bool{}
+	ruleIDs := make([]string, 0, len(findings))
+	for _, f := range findings {
+		if seen[f.Rule] {
+			continue
+		}
+		seen[f.Rule] = true
+		ruleIDs = append(ruleIDs, f.Rule)
+	}
+	sort.Strings(ruleIDs)
+
+	driverRules := make([]Rule, 0, len(ruleIDs))
+	for _, id := range ruleIDs {
+		driverRules = append(driverRules, Rule{
+			ID:               id,
+			ShortDescription: MultiformatText{Text: validator.RuleDescriptions[id]},
+			HelpURI:          toolInfoURI,
+		})
+	}
+
+	results := make([]Result, 0, len(findings))
+	for _, f := range findings {
+		results = append(results, Result{
+			RuleID:  f.Rule,
+			Level:   level(f.Severity),
+			Message: MultiformatText{Text: f.Message},
+			Locations: []Location{{
+				PhysicalLocation: PhysicalLocation{
+					ArtifactLocation: ArtifactLocation{URI: f.File},
+					Region: Region{
+						StartLine:   max1(f.Line),
+						StartColumn: max1(f.Column),
+						EndLine:     f.EndLine,
+						EndColumn:   f.EndColumn,
+					},
+				},
+			}},
+		})
+	}
+
+	return &Log{
+		Schema:  schemaURI,
+		Version: version,
+		Runs: []Run{{
+			Tool: Tool{Driver: Driver{
+				Name:           toolName,
+				InformationURI: toolInfoURI,
+				Version:        toolVersion,
+				Rules:          driverRules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// Marshal renders a Log as indented JSON.
+func Marshal(log *Log) ([]byte, error) {
+	return json.MarshalIndent(log, "", "  ")
+}
+
+func level(sev validator.Severity) string {
+	switch sev {
+	case validator.SeverityWarning:
+		return "warning"
+	case validator.SeverityNote:
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+func max1(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
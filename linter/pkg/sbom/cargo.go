@@ -0,0 +1,129 @@
+package sbom
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// LockedPackage is one `[[package]]` entry from a Cargo.lock.
+type LockedPackage struct {
+	Name     string
+	Version  string
+	Checksum string
+}
+
+// ParseCargoToml reads the `[package]` table of a Cargo.toml and returns its
+// name, version, and license. It's a deliberately small line-oriented parser
+// covering the handful of keys SBOM generation needs, not a general TOML
+// implementation.
+func ParseCargoToml(path string) (name, version, license string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", "", err
+	}
+	defer f.Close()
+
+	inPackage := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			inPackage = line == "[package]"
+			continue
+		}
+		if !inPackage {
+			continue
+		}
+		key, val, ok := splitTomlKV(line)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "name":
+			name = val
+		case "version":
+			version = val
+		case "license":
+			license = val
+		}
+	}
+	return name, version, license, scanner.Err()
+}
+
+// ParseCargoLock reads every `[[package]]` entry from a Cargo.lock.
+func ParseCargoLock(path string) ([]LockedPackage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		pkgs    []LockedPackage
+		current *LockedPackage
+	)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[[") {
+			if current != nil {
+				pkgs = append(pkgs, *current)
+			}
+			if line == "[[package]]" {
+				current = &LockedPackage{}
+			} else {
+				current = nil
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if current != nil {
+				pkgs = append(pkgs, *current)
+				current = nil
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		key, val, ok := splitTomlKV(line)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "name":
+			current.Name = val
+		case "version":
+			current.Version = val
+		case "checksum":
+			current.Checksum = val
+		}
+	}
+	if current != nil {
+		pkgs = append(pkgs, *current)
+	}
+	return pkgs, scanner.Err()
+}
+
+// splitTomlKV splits a `key = "value"` line, unquoting the value. It only
+// handles scalar string values, which is all Cargo.toml/Cargo.lock need here.
+func splitTomlKV(line string) (key, value string, ok bool) {
+	idx := strings.Index(line, "=")
+	if idx < 0 {
+		return "", "", false
+	}
+	key = strings.TrimSpace(line[:idx])
+	value = strings.TrimSpace(line[idx+1:])
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+	return key, value, true
+}
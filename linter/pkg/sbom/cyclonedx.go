@@ -0,0 +1,98 @@
+package sbom
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// cdxComponent is one CycloneDX 1.5 component.
+type cdxComponent struct {
+	Type    string             `json:"type"`
+	Name    string             `json:"name"`
+	Version string             `json:"version"`
+	License []cdxLicenseChoice `json:"licenses,omitempty"`
+	PURL    string             `json:"purl,omitempty"`
+	Hashes  []cdxHash          `json:"hashes,omitempty"`
+	BOMRef  string             `json:"bom-ref"`
+}
+
+type cdxLicenseChoice struct {
+	License cdxLicense `json:"license"`
+}
+
+type cdxLicense struct {
+	ID string `json:"id,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// cdxDocument mirrors the subset of the CycloneDX 1.5 BOM schema this
+// package produces.
+type cdxDocument struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies,omitempty"`
+}
+
+type cdxMetadata struct {
+	Component cdxComponent `json:"component"`
+}
+
+// ToCycloneDX renders the Document as CycloneDX 1.5 JSON.
+func ToCycloneDX(doc *Document) ([]byte, error) {
+	cdx := cdxDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata:    cdxMetadata{Component: toCdxComponent(doc.Root)},
+	}
+
+	deps := map[string][]string{}
+	for _, r := range doc.Relationships {
+		deps[r.From] = append(deps[r.From], r.To)
+	}
+
+	for _, c := range doc.Components {
+		cdx.Components = append(cdx.Components, toCdxComponent(c))
+	}
+
+	refs := make([]string, 0, len(deps))
+	for ref, children := range deps {
+		sort.Strings(children)
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		cdx.Dependencies = append(cdx.Dependencies, cdxDependency{Ref: ref, DependsOn: deps[ref]})
+	}
+
+	return json.MarshalIndent(cdx, "", "  ")
+}
+
+func toCdxComponent(c Component) cdxComponent {
+	out := cdxComponent{
+		Type:    c.Type,
+		Name:    c.Name,
+		Version: c.Version,
+		PURL:    c.PURL,
+		BOMRef:  c.Ref,
+	}
+	if c.License != "" {
+		out.License = []cdxLicenseChoice{{License: cdxLicense{ID: c.License}}}
+	}
+	for _, h := range c.Hashes {
+		out.Hashes = append(out.Hashes, cdxHash{Alg: h.Algorithm, Content: h.Value})
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package sbom
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"strings"
+)
+
+// GoModule is one resolved dependency from a go.sum file.
+type GoModule struct {
+	Path    string
+	Version string
+	H1      string // the "h1:" module-zip hash, base64-encoded
+}
+
+// ParseGoMod returns the module path declared by the `module` directive of a
+// go.mod file.
+func ParseGoMod(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "module")), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// ParseGoSum reads a go.sum and returns one GoModule per module@version,
+// preferring the module-zip hash line over the companion "/go.mod" line.
+func ParseGoSum(path string) ([]GoModule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	byKey := map[string]*GoModule{}
+	var order []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		modPath, version, h1 := fields[0], fields[1], fields[2]
+		isGoModHash := strings.HasSuffix(version, "/go.mod")
+		version = strings.TrimSuffix(version, "/go.mod")
+
+		key := modPath + "@" + version
+		m, ok := byKey[key]
+		if !ok {
+			m = &GoModule{Path: modPath, Version: version}
+			byKey[key] = m
+			order = append(order, key)
+		}
+		// Prefer the module-zip hash over its "/go.mod"-only companion line.
+		if !isGoModHash || m.H1 == "" {
+			m.H1 = h1
+		}
+	}
+
+	out := make([]GoModule, 0, len(order))
+	for _, key := range order {
+		out = append(out, *byKey[key])
+	}
+	return out, scanner.Err()
+}
+
+// hashFromH1 decodes a go.sum "h1:<base64>" dirhash into the SHA-256 Hash
+// CycloneDX/SPDX expect (hex-encoded, under the standard algorithm name),
+// rather than passing the base64 blob through under a non-standard "H1"
+// algorithm. It reports false if h1 isn't a well-formed h1 hash.
+func hashFromH1(h1 string) (Hash, bool) {
+	b64 := strings.TrimPrefix(h1, "h1:")
+	if b64 == h1 {
+		return Hash{}, false
+	}
+	sum, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return Hash{}, false
+	}
+	return Hash{Algorithm: "SHA-256", Value: hex.EncodeToString(sum)}, true
+}
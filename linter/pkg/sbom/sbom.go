@@ -0,0 +1,237 @@
+// Package sbom generates a Software Bill of Materials for a QNet
+// implementation directly, without shelling out to an external tool. It
+// discovers Cargo (Rust) and Go manifests under the analyzed path, resolves
+// component name/version/license/hash, and renders the result as CycloneDX
+// 1.5 or SPDX 2.3 JSON.
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Hash is a single cryptographic digest of a Component.
+type Hash struct {
+	Algorithm string
+	Value     string
+}
+
+// Component is one piece of software discovered in the analyzed tree: the
+// root implementation itself, or one of its Cargo/Go dependencies.
+type Component struct {
+	Ref       string // stable identifier used in Relationships
+	Type      string // "application" for the root component, "library" otherwise
+	Name      string
+	Version   string
+	License   string
+	Ecosystem string // "cargo" or "go"
+	PURL      string
+	Hashes    []Hash
+}
+
+// Relationship records that From depends on To.
+type Relationship struct {
+	From string
+	To   string
+	Type string // always "DEPENDS_ON" today
+}
+
+// Document is a fully-resolved SBOM, ecosystem-agnostic until rendered.
+type Document struct {
+	Root          Component
+	Components    []Component
+	Relationships []Relationship
+}
+
+// Generate walks path, discovers Cargo and Go manifests, and builds a
+// Document describing the analyzed implementation and its dependencies.
+func Generate(path string) (*Document, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	treeHash, err := hashTree(abs)
+	if err != nil {
+		return nil, fmt.Errorf("sbom: hashing source tree: %w", err)
+	}
+
+	root := Component{
+		Ref:       "root",
+		Type:      "application",
+		Name:      filepath.Base(abs),
+		Version:   "0.0.0-unversioned",
+		Ecosystem: "source",
+		Hashes:    []Hash{{Algorithm: "SHA-256", Value: treeHash}},
+	}
+
+	doc := &Document{Root: root}
+
+	cargoTomls, err := findFiles(abs, "Cargo.toml")
+	if err != nil {
+		return nil, err
+	}
+	for _, ct := range cargoTomls {
+		if name, version, license, err := ParseCargoToml(ct); err == nil && name != "" {
+			doc.Root.Name = name
+			if version != "" {
+				doc.Root.Version = version
+			}
+			doc.Root.License = license
+			break
+		}
+	}
+
+	cargoLocks, err := findFiles(abs, "Cargo.lock")
+	if err != nil {
+		return nil, err
+	}
+	for _, cl := range cargoLocks {
+		pkgs, err := ParseCargoLock(cl)
+		if err != nil {
+			return nil, fmt.Errorf("sbom: parsing %s: %w", cl, err)
+		}
+		for _, p := range pkgs {
+			ref := "cargo:" + p.Name + "@" + p.Version
+			c := Component{
+				Ref:       ref,
+				Type:      "library",
+				Name:      p.Name,
+				Version:   p.Version,
+				Ecosystem: "cargo",
+				PURL:      fmt.Sprintf("pkg:cargo/%s@%s", p.Name, p.Version),
+			}
+			if p.Checksum != "" {
+				c.Hashes = []Hash{{Algorithm: "SHA-256", Value: p.Checksum}}
+			}
+			doc.Components = append(doc.Components, c)
+			doc.Relationships = append(doc.Relationships, Relationship{From: "root", To: ref, Type: "DEPENDS_ON"})
+		}
+	}
+
+	goMods, err := findFiles(abs, "go.mod")
+	if err != nil {
+		return nil, err
+	}
+	for _, gm := range goMods {
+		if doc.Root.Ecosystem == "source" {
+			if modPath, err := ParseGoMod(gm); err == nil && modPath != "" {
+				doc.Root.Name = modPath
+				doc.Root.Ecosystem = "go"
+			}
+		}
+		sum := filepath.Join(filepath.Dir(gm), "go.sum")
+		mods, err := ParseGoSum(sum)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("sbom: parsing %s: %w", sum, err)
+		}
+		for _, m := range mods {
+			ref := "go:" + m.Path + "@" + m.Version
+			c := Component{
+				Ref:       ref,
+				Type:      "library",
+				Name:      m.Path,
+				Version:   m.Version,
+				Ecosystem: "go",
+				PURL:      fmt.Sprintf("pkg:golang/%s@%s", m.Path, m.Version),
+			}
+			if h, ok := hashFromH1(m.H1); ok {
+				c.Hashes = []Hash{h}
+			}
+			doc.Components = append(doc.Components, c)
+			doc.Relationships = append(doc.Relationships, Relationship{From: "root", To: ref, Type: "DEPENDS_ON"})
+		}
+	}
+
+	sort.Slice(doc.Components, func(i, j int) bool {
+		if doc.Components[i].Ecosystem != doc.Components[j].Ecosystem {
+			return doc.Components[i].Ecosystem < doc.Components[j].Ecosystem
+		}
+		return doc.Components[i].Name < doc.Components[j].Name
+	})
+
+	return doc, nil
+}
+
+// hashTree computes a single SHA-256 digest over every regular file under
+// root (skipping VCS and build-output directories), so the resulting SBOM
+// can later be used to verify the analyzed tree hasn't changed.
+func hashTree(root string) (string, error) {
+	type entry struct {
+		rel  string
+		hash string
+	}
+	var entries []entry
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "target", "node_modules":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{rel: filepath.ToSlash(rel), hash: hex.EncodeToString(h.Sum(nil))})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].rel < entries[j].rel })
+
+	tree := sha256.New()
+	for _, e := range entries {
+		fmt.Fprintf(tree, "%s:%s\n", e.rel, e.hash)
+	}
+	return hex.EncodeToString(tree.Sum(nil)), nil
+}
+
+func findFiles(dir, name string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") && d.Name() != "." {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == name {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
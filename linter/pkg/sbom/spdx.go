@@ -0,0 +1,144 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	DownloadLocation string            `json:"downloadLocation"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxDocument mirrors the subset of the SPDX 2.3 JSON schema this package
+// produces.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Creators []string `json:"creators"`
+}
+
+const spdxNoAssertion = "NOASSERTION"
+
+// ToSPDX renders the Document as SPDX 2.3 JSON.
+func ToSPDX(doc *Document) ([]byte, error) {
+	rootID := "SPDXRef-root"
+	out := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              doc.Root.Name,
+		DocumentNamespace: fmt.Sprintf("https://qnet.invalid/spdx/%s-%s", doc.Root.Name, doc.Root.Version),
+		CreationInfo:      spdxCreationInfo{Creators: []string{"Tool: qnet-lint"}},
+	}
+
+	out.Packages = append(out.Packages, toSpdxPackage(rootID, doc.Root))
+
+	refByComponentRef := map[string]string{"root": rootID}
+	for i, c := range doc.Components {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i)
+		refByComponentRef[c.Ref] = id
+		out.Packages = append(out.Packages, toSpdxPackage(id, c))
+	}
+
+	for _, r := range doc.Relationships {
+		from, ok1 := refByComponentRef[r.From]
+		to, ok2 := refByComponentRef[r.To]
+		if !ok1 || !ok2 {
+			continue
+		}
+		out.Relationships = append(out.Relationships, spdxRelationship{
+			SPDXElementID:      from,
+			RelationshipType:   r.Type,
+			RelatedSPDXElement: to,
+		})
+	}
+	sort.Slice(out.Relationships, func(i, j int) bool {
+		if out.Relationships[i].SPDXElementID != out.Relationships[j].SPDXElementID {
+			return out.Relationships[i].SPDXElementID < out.Relationships[j].SPDXElementID
+		}
+		return out.Relationships[i].RelatedSPDXElement < out.Relationships[j].RelatedSPDXElement
+	})
+
+	return json.MarshalIndent(out, "", "  ")
+}
+
+func toSpdxPackage(id string, c Component) spdxPackage {
+	license := spdxNoAssertion
+	if c.License != "" {
+		license = c.License
+	}
+	pkg := spdxPackage{
+		SPDXID:           id,
+		Name:             c.Name,
+		VersionInfo:      c.Version,
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  license,
+		DownloadLocation: spdxNoAssertion,
+	}
+	for _, h := range c.Hashes {
+		pkg.Checksums = append(pkg.Checksums, spdxChecksum{Algorithm: spdxAlgorithm(h.Algorithm), ChecksumValue: h.Value})
+	}
+	if c.PURL != "" {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  c.PURL,
+		})
+	}
+	return pkg
+}
+
+// spdxAlgorithm translates a Hash.Algorithm (CycloneDX's hyphenated form,
+// e.g. "SHA-256") into SPDX 2.3's checksums[].algorithm enum, which omits
+// the hyphen (e.g. "SHA256"). Unrecognized algorithms pass through
+// unchanged rather than being dropped.
+func spdxAlgorithm(alg string) string {
+	switch alg {
+	case "SHA-256":
+		return "SHA256"
+	case "SHA-1":
+		return "SHA1"
+	case "SHA-384":
+		return "SHA384"
+	case "SHA-512":
+		return "SHA512"
+	case "MD5":
+		return "MD5"
+	default:
+		return alg
+	}
+}
@@ -0,0 +1,45 @@
+package sbom
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSpdxAlgorithmTranslation guards against SPDX 2.3's unhyphenated
+// checksum algorithm enum (SHA256) regressing back to CycloneDX's
+// hyphenated spelling (SHA-256), which SPDX validators reject.
+func TestSpdxAlgorithmTranslation(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"SHA-256", "SHA256"},
+		{"SHA-1", "SHA1"},
+		{"unknown-alg", "unknown-alg"},
+	}
+	for _, c := range cases {
+		if got := spdxAlgorithm(c.in); got != c.want {
+			t.Errorf("spdxAlgorithm(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestToSPDXUsesUnhyphenatedAlgorithm(t *testing.T) {
+	doc := &Document{
+		Root: Component{
+			Ref:     "root",
+			Name:    "example",
+			Version: "1.0.0",
+			Hashes:  []Hash{{Algorithm: "SHA-256", Value: "deadbeef"}},
+		},
+	}
+	out, err := ToSPDX(doc)
+	if err != nil {
+		t.Fatalf("ToSPDX: %v", err)
+	}
+	if !strings.Contains(string(out), `"algorithm": "SHA256"`) {
+		t.Fatalf("ToSPDX output does not contain SPDX-conformant SHA256 algorithm:\n%s", out)
+	}
+	if strings.Contains(string(out), `"algorithm": "SHA-256"`) {
+		t.Fatalf("ToSPDX output still contains CycloneDX's hyphenated SHA-256 algorithm:\n%s", out)
+	}
+}
@@ -1,169 +1,391 @@
+// Package validator checks a Rust QNet implementation against the QNet
+// specification. Rules parse the implementation's Rust source into an AST
+// (via linter/pkg/rustast) and reason about function bodies, call
+// expressions, and guard conditions, rather than grepping raw text.
 package validator
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/QW1CKS/qnet/linter/pkg/rustast"
 )
 
-// ValidationRule represents a compliance rule
+// Severity is how serious a Finding is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNote    Severity = "note"
+)
+
+// Finding is a single structured compliance result produced by a
+// ValidationRule. File/Line/Column (and optionally EndLine/EndColumn)
+// pinpoint the offending construct in the Rust source so tooling like SARIF
+// export can render it inline.
+type Finding struct {
+	Rule      string   `json:"rule"`
+	Message   string   `json:"message"`
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	Column    int      `json:"column"`
+	EndLine   int      `json:"endLine,omitempty"`
+	EndColumn int      `json:"endColumn,omitempty"`
+	Severity  Severity `json:"severity"`
+}
+
+// ValidationRule is a single pluggable QNet spec check. Check walks the Rust
+// sources under path and returns one Finding per violation it locates; an
+// empty slice with a nil error means the rule found nothing to complain
+// about.
 type ValidationRule struct {
 	Name        string
 	Description string
-	Check       func(path string) error
+	Check       func(path string) ([]Finding, error)
 }
 
-// ValidateL2Framing checks L2 framing compliance
-func ValidateL2Framing(path string) error {
-	// Look for framing code in Rust files
-	rustFiles, err := findFiles(path, "*.rs")
+// Rules is the set of QNet compliance checks run by Validate.
+var Rules = []ValidationRule{
+	{
+		Name:        "L2 Framing",
+		Description: "Checks AEAD protection and length validation",
+		Check:       ValidateL2Framing,
+	},
+	{
+		Name:        "TemplateID",
+		Description: "Checks deterministic CBOR and SHA-256 computation",
+		Check:       ValidateTemplateID,
+	},
+	{
+		Name:        "KEY_UPDATE",
+		Description: "Checks 3-frame overlap and nonce lifecycle",
+		Check:       ValidateKeyUpdate,
+	},
+	{
+		Name:        "BN-Ticket",
+		Description: "Checks 256-byte header validation",
+		Check:       ValidateBNTicket,
+	},
+}
+
+// RuleDescriptions maps each fine-grained Finding.Rule value (not the
+// coarser ValidationRule.Name the rule is registered under) to a short
+// human-readable description, so tooling that reports per-finding metadata
+// (e.g. SARIF's tool.driver.rules) can describe the rule a Result actually
+// references instead of the broader check it came from.
+var RuleDescriptions = map[string]string{
+	"L2-FRAMING-SEAL":     "Frame::encode must invoke an AEAD seal over its payload",
+	"L2-FRAMING-OPEN":     "Frame::decode must invoke an AEAD open over its payload",
+	"L2-FRAMING-LENGTH":   "Frame::decode must reject frames shorter than 24 bytes before processing them",
+	"TEMPLATE-ID-MISSING": "compute_template_id must be implemented",
+	"TEMPLATE-ID-HASH":    "compute_template_id must invoke a SHA-256 digest over its input",
+	"KEY-UPDATE-OVERLAP":  "the KEY_UPDATE handler must keep an overlap window alive for the retiring key",
+	"KEY-UPDATE-WINDOW":   "the KEY_UPDATE handler must size its overlap window to 3 frames",
+	"KEY-UPDATE-MISSING":  "a KEY_UPDATE handler must be implemented",
+	"BN-TICKET-LENGTH":    "BN-Ticket header parsing must reject headers longer than 256 bytes up front",
+	"BN-TICKET-MISSING":   "BN-Ticket header parsing must be implemented",
+}
+
+// lenLT24 and gt256 require a whole-number comparison against an identifier
+// or call (not just the digits appearing anywhere), so a guard like
+// `buf.len() < 2400` or `counter > 25600` doesn't satisfy a "< 24"/"> 256"
+// check just because "24"/"256" appears as a substring of a larger number.
+// rustast's condition strings space out tokens (`buf . len () < 24`), so the
+// identifier/method-chain portion tolerates whitespace around dots and calls.
+var identOrCall = `[A-Za-z_]\w*(?:\s*\.\s*[A-Za-z_]\w*)*\s*(?:\([^)]*\))?`
+
+var lenLT24 = regexp.MustCompile(identOrCall + `\s*<\s*24\b`)
+var gt256 = regexp.MustCompile(identOrCall + `\s*>\s*256\b`)
+
+// ValidateL2Framing checks that Frame::encode/decode seal and open an AEAD
+// payload, and that decode rejects undersized frames before doing anything
+// else with them.
+func ValidateL2Framing(path string) ([]Finding, error) {
+	files, err := parseRustFiles(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, file := range rustFiles {
-		content, err := os.ReadFile(file)
-		if err != nil {
+	var findings []Finding
+	for _, f := range files {
+		encode := findFunction(f, "Frame", "encode")
+		decode := findFunction(f, "Frame", "decode")
+		if encode == nil && decode == nil {
 			continue
 		}
 
-		// Check for frame encoding/decoding
-		if !strings.Contains(string(content), "Frame::encode") ||
-		   !strings.Contains(string(content), "Frame::decode") {
+		if encode != nil && !encode.HasCall("seal") {
+			findings = append(findings, finding("L2-FRAMING-SEAL", "Frame::encode never calls an AEAD seal", f.Path, *encode, SeverityError))
+		}
+		if decode == nil {
 			continue
 		}
-
-		// Check for AEAD protection
-		if !strings.Contains(string(content), "seal") ||
-		   !strings.Contains(string(content), "open") {
-			return fmt.Errorf("L2 framing in %s missing AEAD protection", file)
+		if !decode.HasCall("open") {
+			findings = append(findings, finding("L2-FRAMING-OPEN", "Frame::decode never calls an AEAD open", f.Path, *decode, SeverityError))
 		}
 
-		// Check for length checks
-		if !regexp.MustCompile(`len.*<.*24`).MatchString(string(content)) {
-			return fmt.Errorf("L2 framing in %s missing length validation", file)
+		guard := decode.FirstIf()
+		if guard == nil || !lenLT24.MatchString(guard.Condition) {
+			findings = append(findings, Finding{
+				Rule:     "L2-FRAMING-LENGTH",
+				Message:  "Frame::decode does not guard against frames shorter than 24 bytes before processing them",
+				File:     f.Path,
+				Line:     decode.StartLine,
+				Column:   1,
+				Severity: SeverityError,
+			})
 		}
 	}
 
-	return nil
+	return findings, nil
 }
 
-// ValidateTemplateID checks TemplateID compliance
-func ValidateTemplateID(path string) error {
-	rustFiles, err := findFiles(path, "*.rs")
+// ValidateTemplateID checks that compute_template_id exists and actually
+// hashes its input, rather than just mentioning TemplateID somewhere.
+func ValidateTemplateID(path string) ([]Finding, error) {
+	files, err := parseRustFiles(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	found := false
-	for _, file := range rustFiles {
-		content, err := os.ReadFile(file)
-		if err != nil {
-			continue
-		}
-
-		if strings.Contains(string(content), "compute_template_id") ||
-		   strings.Contains(string(content), "TemplateID") {
-			found = true
+	var fn *rustast.Function
+	var fnFile string
+	for _, f := range files {
+		if got := findFunction(f, "", "compute_template_id"); got != nil {
+			fn = got
+			fnFile = f.Path
 			break
 		}
 	}
+	if fn == nil {
+		return []Finding{{
+			Rule:     "TEMPLATE-ID-MISSING",
+			Message:  "compute_template_id implementation not found",
+			File:     path,
+			Severity: SeverityError,
+		}}, nil
+	}
 
-	if !found {
-		return fmt.Errorf("TemplateID implementation not found")
+	if !fn.HasCall("update") && !fn.HasCall("finalize") && !hasAnyCall(*fn, "sha256", "Sha256") {
+		return []Finding{{
+			Rule:     "TEMPLATE-ID-HASH",
+			Message:  "compute_template_id does not invoke a SHA-256 digest",
+			File:     fnFile,
+			Line:     fn.StartLine,
+			Column:   1,
+			Severity: SeverityError,
+		}}, nil
 	}
 
-	return nil
+	return nil, nil
 }
 
-// ValidateKeyUpdate checks KEY_UPDATE compliance
-func ValidateKeyUpdate(path string) error {
-	rustFiles, err := findFiles(path, "*.rs")
+// ValidateKeyUpdate checks that the KEY_UPDATE handler keeps a 3-frame
+// overlap window alive rather than tearing down the old key immediately.
+func ValidateKeyUpdate(path string) ([]Finding, error) {
+	files, err := parseRustFiles(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, file := range rustFiles {
-		content, err := os.ReadFile(file)
-		if err != nil {
-			continue
-		}
-
-		if strings.Contains(string(content), "KEY_UPDATE") {
-			// Check for 3-frame overlap
-			if !strings.Contains(string(content), "overlap") &&
-			   !strings.Contains(string(content), "3") {
-				return fmt.Errorf("KEY_UPDATE in %s missing 3-frame overlap", file)
+	var findings []Finding
+	handlerFound := false
+	for _, f := range files {
+		for _, fn := range f.Functions {
+			if !strings.Contains(strings.ToLower(fn.Name), "key_update") {
+				continue
+			}
+			handlerFound = true
+			if !hasCallOrIfMentioning(fn, "overlap") {
+				findings = append(findings, Finding{
+					Rule:     "KEY-UPDATE-OVERLAP",
+					Message:  fmt.Sprintf("%s has no overlap-window handling for the retiring key", fn.Name),
+					File:     f.Path,
+					Line:     fn.StartLine,
+					Column:   1,
+					Severity: SeverityError,
+				})
+				continue
+			}
+			if !sizesOverlapWindowTo(fn, "3") {
+				findings = append(findings, Finding{
+					Rule:     "KEY-UPDATE-WINDOW",
+					Message:  fmt.Sprintf("%s does not size its overlap window to 3 frames", fn.Name),
+					File:     f.Path,
+					Line:     fn.StartLine,
+					Column:   1,
+					Severity: SeverityWarning,
+				})
 			}
-			break
 		}
 	}
 
-	return nil
+	if !handlerFound {
+		return []Finding{{
+			Rule:     "KEY-UPDATE-MISSING",
+			Message:  "no KEY_UPDATE handler found",
+			File:     path,
+			Severity: SeverityError,
+		}}, nil
+	}
+
+	return findings, nil
 }
 
-// ValidateBNTicket checks BN-Ticket header compliance
-func ValidateBNTicket(path string) error {
-	rustFiles, err := findFiles(path, "*.rs")
+// ValidateBNTicket checks that BN-Ticket header parsing rejects inputs over
+// 256 bytes before the first branch does anything else with them.
+func ValidateBNTicket(path string) ([]Finding, error) {
+	files, err := parseRustFiles(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, file := range rustFiles {
-		content, err := os.ReadFile(file)
+	var findings []Finding
+	handlerFound := false
+	for _, f := range files {
+		for _, fn := range f.Functions {
+			name := strings.ToLower(fn.Name)
+			if !strings.Contains(name, "bn_ticket") && !strings.Contains(name, "ticket") {
+				continue
+			}
+			handlerFound = true
+			guard := fn.FirstIf()
+			if guard == nil || !gt256.MatchString(guard.Condition) {
+				findings = append(findings, Finding{
+					Rule:     "BN-TICKET-LENGTH",
+					Message:  fmt.Sprintf("%s does not reject headers longer than 256 bytes up front", fn.Name),
+					File:     f.Path,
+					Line:     fn.StartLine,
+					Column:   1,
+					Severity: SeverityError,
+				})
+			}
+		}
+	}
+
+	if !handlerFound {
+		return []Finding{{
+			Rule:     "BN-TICKET-MISSING",
+			Message:  "BN-Ticket header parsing not found",
+			File:     path,
+			Severity: SeverityError,
+		}}, nil
+	}
+
+	return findings, nil
+}
+
+// Validate runs every rule in Rules against path and returns the combined
+// findings in rule order.
+func Validate(path string) ([]Finding, error) {
+	var all []Finding
+	for _, rule := range Rules {
+		findings, err := rule.Check(path)
 		if err != nil {
+			return all, fmt.Errorf("%s: %w", rule.Name, err)
+		}
+		all = append(all, findings...)
+	}
+	return all, nil
+}
+
+// ToJSON renders findings as the raw JSON finding stream consumed by
+// `qnet-lint validate --format json`.
+func ToJSON(findings []Finding) ([]byte, error) {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	return json.MarshalIndent(findings, "", "  ")
+}
+
+func finding(rule, msg, file string, fn rustast.Function, sev Severity) Finding {
+	return Finding{
+		Rule:     rule,
+		Message:  msg,
+		File:     file,
+		Line:     fn.StartLine,
+		Column:   1,
+		EndLine:  fn.EndLine,
+		Severity: sev,
+	}
+}
+
+func findFunction(f *rustast.File, implType, name string) *rustast.Function {
+	for i := range f.Functions {
+		fn := &f.Functions[i]
+		if fn.Name != name {
 			continue
 		}
+		if implType != "" && fn.ImplType != implType {
+			continue
+		}
+		return fn
+	}
+	return nil
+}
 
-		if strings.Contains(string(content), "BN-Ticket") ||
-		   strings.Contains(string(content), "256") {
-			// Check for 256-byte limit
-			if !strings.Contains(string(content), "256") {
-				return fmt.Errorf("BN-Ticket in %s missing 256-byte validation", file)
-			}
-			break
+func hasAnyCall(fn rustast.Function, names ...string) bool {
+	for _, n := range names {
+		if fn.HasCall(n) {
+			return true
 		}
 	}
+	return false
+}
 
-	return nil
+func hasCallOrIfMentioning(fn rustast.Function, substr string) bool {
+	substr = strings.ToLower(substr)
+	for _, c := range fn.Calls {
+		if strings.Contains(strings.ToLower(c.Name), substr) {
+			return true
+		}
+	}
+	for _, ie := range fn.Ifs {
+		if strings.Contains(strings.ToLower(ie.Condition), substr) {
+			return true
+		}
+	}
+	return false
 }
 
-// Validate runs all validation rules
-func Validate(path string) []error {
-	var errors []error
-
-	rules := []ValidationRule{
-		{
-			Name:        "L2 Framing",
-			Description: "Checks AEAD protection and length validation",
-			Check:       ValidateL2Framing,
-		},
-		{
-			Name:        "TemplateID",
-			Description: "Checks deterministic CBOR and SHA-256 computation",
-			Check:       ValidateTemplateID,
-		},
-		{
-			Name:        "KEY_UPDATE",
-			Description: "Checks 3-frame overlap and nonce lifecycle",
-			Check:       ValidateKeyUpdate,
-		},
-		{
-			Name:        "BN-Ticket",
-			Description: "Checks 256-byte header validation",
-			Check:       ValidateBNTicket,
-		},
-	}
-
-	for _, rule := range rules {
-		if err := rule.Check(path); err != nil {
-			errors = append(errors, fmt.Errorf("%s: %v", rule.Name, err))
-		}
-	}
-
-	return errors
+// sizesOverlapWindowTo reports whether fn has an if-condition that actually
+// compares a variable against literal as a whole number, e.g. `n == 3` or
+// `retries < 3`. Unlike a bare substring match, this rejects conditions
+// where literal only happens to appear inside a longer number (`idx == 13`)
+// or a call name (`retry3times()`), which don't size anything to literal.
+func sizesOverlapWindowTo(fn rustast.Function, literal string) bool {
+	re := regexp.MustCompile(`[A-Za-z_]\w*\s*(==|!=|<=|>=|<|>)\s*` + literal + `\b|\b` + literal + `\s*(==|!=|<=|>=|<|>)\s*[A-Za-z_]\w*`)
+	for _, ie := range fn.Ifs {
+		if re.MatchString(ie.Condition) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRustFiles parses every .rs file under path with rustast.Parse,
+// skipping (rather than failing on) files the helper can't parse so a single
+// malformed vendor file doesn't take down the whole run.
+func parseRustFiles(path string) ([]*rustast.File, error) {
+	rustFiles, err := findFiles(path, "*.rs")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*rustast.File
+	for _, rf := range rustFiles {
+		f, err := rustast.Parse(rf)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, nil
 }
 
 // findFiles finds files matching pattern in directory
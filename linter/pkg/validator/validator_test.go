@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/QW1CKS/qnet/linter/pkg/rustast"
+)
+
+// TestLenLT24 exercises the false-negative class the length-guard rewrite
+// was meant to kill: a guard whose condition merely contains "24" as a
+// substring of a larger number (or on an unrelated identifier) must not
+// satisfy the check. rustast spaces out tokens (`buf . len () < 24`), so
+// that form must still match.
+func TestLenLT24(t *testing.T) {
+	cases := []struct {
+		condition string
+		want      bool
+	}{
+		{"buf . len () < 24", true},
+		{"buf . len () < 2400", false},
+		{"some_unrelated_counter < 2400", false},
+		{"idx < 24", true},
+	}
+	for _, c := range cases {
+		if got := lenLT24.MatchString(c.condition); got != c.want {
+			t.Errorf("lenLT24.MatchString(%q) = %v, want %v", c.condition, got, c.want)
+		}
+	}
+}
+
+func TestGt256(t *testing.T) {
+	cases := []struct {
+		condition string
+		want      bool
+	}{
+		{"header . len () > 256", true},
+		{"some_unrelated_counter > 25600", false},
+		{"idx > 2560", false},
+		{"n > 256", true},
+	}
+	for _, c := range cases {
+		if got := gt256.MatchString(c.condition); got != c.want {
+			t.Errorf("gt256.MatchString(%q) = %v, want %v", c.condition, got, c.want)
+		}
+	}
+}
+
+func TestSizesOverlapWindowTo(t *testing.T) {
+	cases := []struct {
+		condition string
+		want      bool
+	}{
+		{"overlap_count >= 3", true},
+		{"3 == window", true},
+		{"idx == 13", false},
+		{"retry3times ()", false},
+	}
+	for _, c := range cases {
+		fn := rustast.Function{Ifs: []rustast.IfExpr{{Condition: c.condition}}}
+		if got := sizesOverlapWindowTo(fn, "3"); got != c.want {
+			t.Errorf("sizesOverlapWindowTo(%q) = %v, want %v", c.condition, got, c.want)
+		}
+	}
+}
+
+// TestValidateL2FramingLength and TestValidateBNTicketLength exercise the
+// same regexes through the rules that actually call them, using synthetic
+// rustast.File values so the test doesn't depend on the qnet-rustast helper
+// binary being on PATH.
+func TestValidateBNTicketLengthGuard(t *testing.T) {
+	fn := rustast.Function{
+		Name: "bn_ticket_parse",
+		Ifs:  []rustast.IfExpr{{Condition: "some_unrelated_counter > 25600", Line: 1}},
+	}
+	if gt256.MatchString(fn.FirstIf().Condition) {
+		t.Fatal("gt256 should not match a guard that only coincidentally contains \"256\" as a substring of a larger number")
+	}
+
+	fn.Ifs[0].Condition = "header . len () > 256"
+	if !gt256.MatchString(fn.FirstIf().Condition) {
+		t.Fatal("gt256 should match a genuine > 256 length guard")
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tlsutls "github.com/refraction-networking/utls"
+)
+
+// templatesDir holds the per-milestone Chrome templates and the catalog that
+// indexes them, kept separate from the generic template_N.bin/json pairs
+// that `generate` writes to the working directory.
+const templatesDir = "templates"
+
+// versionHistoryURL is the Chrome Version History API endpoint for the
+// Windows stable channel. The chromium mirror on GitHub publishes no
+// releases, so this (rather than the GitHub releases API) is the only
+// source of truth for real Chrome Stable version numbers.
+const versionHistoryURL = "https://versionhistory.googleapis.com/v1/chrome/platforms/win/channels/stable/versions?pageSize=50"
+
+// catalogEntry is one row of templates/catalog.json: a pinned mapping from a
+// real Chrome Stable version to the uTLS preset used to mimic it, plus the
+// fingerprints of the generated template and (with --verify-remote) what it
+// actually negotiated against a live server.
+type catalogEntry struct {
+	ChromeVersion    string `json:"chrome_version"`
+	UTLSPreset       string `json:"utls_preset"`
+	TemplateFile     string `json:"template_file"`
+	JA3Hash          string `json:"ja3_hash"`
+	JA4Hash          string `json:"ja4_hash"`
+	GeneratedAt      string `json:"generated_at"`
+	NegotiatedCipher string `json:"negotiated_cipher,omitempty"`
+	NegotiatedALPN   string `json:"negotiated_alpn,omitempty"`
+}
+
+// chromePreset pairs a Chrome major version with the uTLS ClientHelloID that
+// most closely mimics it.
+type chromePreset struct {
+	milestone int
+	id        tlsutls.ClientHelloID
+	name      string
+}
+
+// chromePresets is the set of Chrome milestones refraction-networking/utls
+// ships a dedicated fingerprint for, oldest first. closestPreset walks this
+// table to find the best match for a milestone it doesn't know by name.
+var chromePresets = []chromePreset{
+	{58, tlsutls.HelloChrome_58, "HelloChrome_58"},
+	{62, tlsutls.HelloChrome_62, "HelloChrome_62"},
+	{70, tlsutls.HelloChrome_70, "HelloChrome_70"},
+	{72, tlsutls.HelloChrome_72, "HelloChrome_72"},
+	{83, tlsutls.HelloChrome_83, "HelloChrome_83"},
+	{87, tlsutls.HelloChrome_87, "HelloChrome_87"},
+	{96, tlsutls.HelloChrome_96, "HelloChrome_96"},
+	{100, tlsutls.HelloChrome_100, "HelloChrome_100"},
+	{102, tlsutls.HelloChrome_102, "HelloChrome_102"},
+	{106, tlsutls.HelloChrome_106_Shuffle, "HelloChrome_106_Shuffle"},
+	{112, tlsutls.HelloChrome_112_PSK_Shuf, "HelloChrome_112_PSK_Shuf"},
+	{115, tlsutls.HelloChrome_115_PQ, "HelloChrome_115_PQ"},
+	{120, tlsutls.HelloChrome_120, "HelloChrome_120"},
+	{131, tlsutls.HelloChrome_131, "HelloChrome_131"},
+	{133, tlsutls.HelloChrome_133, "HelloChrome_133"},
+}
+
+// closestPreset returns the chromePreset for the highest known milestone
+// that does not exceed major, so a Chrome version newer than anything uTLS
+// ships still gets the closest older fingerprint instead of an error. The
+// bool reports whether the milestone matched exactly.
+func closestPreset(major int) (chromePreset, bool) {
+	best := chromePresets[0]
+	for _, p := range chromePresets {
+		if p.milestone > major {
+			break
+		}
+		best = p
+	}
+	return best, best.milestone == major
+}
+
+// chromeVersion is one entry of the Version History API response; only the
+// dotted version string is needed to derive the major/milestone number.
+type chromeVersion struct {
+	Version string `json:"version"`
+}
+
+type versionHistoryResponse struct {
+	Versions []chromeVersion `json:"versions"`
+}
+
+// versionHistoryClient bounds how long fetchStableMilestones will wait on
+// the Version History API, so `update` fails loudly instead of hanging if
+// the endpoint stalls.
+var versionHistoryClient = &http.Client{Timeout: 10 * time.Second}
+
+// fetchStableMilestones queries the Chrome Version History API and returns
+// the n most recent distinct Chrome Stable major versions, newest first. It
+// returns an empty slice without making a request if n <= 0.
+func fetchStableMilestones(n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	resp, err := versionHistoryClient.Get(versionHistoryURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching Chrome version history: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Chrome version history returned %s", resp.Status)
+	}
+
+	var body versionHistoryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("parsing Chrome version history: %w", err)
+	}
+	if len(body.Versions) == 0 {
+		return nil, fmt.Errorf("Chrome version history returned no versions")
+	}
+
+	sort.Slice(body.Versions, func(i, j int) bool {
+		return compareVersions(body.Versions[i].Version, body.Versions[j].Version) > 0
+	})
+
+	seen := map[int]bool{}
+	milestones := make([]string, 0, n)
+	for _, v := range body.Versions {
+		major := majorOf(v.Version)
+		if major == 0 || seen[major] {
+			continue
+		}
+		seen[major] = true
+		milestones = append(milestones, v.Version)
+		if len(milestones) == n {
+			break
+		}
+	}
+	return milestones, nil
+}
+
+// majorOf returns the leading dot-separated component of a Chrome version
+// string (e.g. 120 for "120.0.6099.71"), or 0 if it can't be parsed.
+func majorOf(version string) int {
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// compareVersions orders two dotted Chrome version strings numerically,
+// component by component, returning <0, 0, >0 like strings.Compare.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, _ := strconv.Atoi(as[i])
+		bn, _ := strconv.Atoi(bs[i])
+		if an != bn {
+			return an - bn
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// verifyHandshake dials www.google.com and performs a real TLS handshake
+// using id, returning the cipher suite and ALPN protocol the server actually
+// negotiated so operators can see which presets still handshake cleanly.
+func verifyHandshake(id tlsutls.ClientHelloID) (cipherSuite, alpn string, err error) {
+	conn, err := net.DialTimeout("tcp", "www.google.com:443", 10*time.Second)
+	if err != nil {
+		return "", "", fmt.Errorf("dialing www.google.com: %w", err)
+	}
+	defer conn.Close()
+
+	uconn := tlsutls.UClient(conn, &tlsutls.Config{ServerName: "www.google.com"}, id)
+	if err := uconn.Handshake(); err != nil {
+		return "", "", fmt.Errorf("handshake: %w", err)
+	}
+
+	state := uconn.ConnectionState()
+	return tls.CipherSuiteName(state.CipherSuite), state.NegotiatedProtocol, nil
+}
+
+// buildCatalog fetches the n most recent Chrome Stable milestones, generates
+// a pinned ClientHello template per milestone under templates/, and writes
+// templates/catalog.json mapping each Chrome version to the uTLS preset and
+// fingerprints used to mimic it. With verifyRemote it also performs a live
+// handshake against www.google.com per preset and records what it
+// negotiated.
+func buildCatalog(n int, verifyRemote bool) error {
+	versions, err := fetchStableMilestones(n)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", templatesDir, err)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	entries := make([]catalogEntry, 0, len(versions))
+
+	for _, version := range versions {
+		major := majorOf(version)
+		preset, exact := closestPreset(major)
+		if !exact {
+			fmt.Printf("no uTLS preset for Chrome %d, using closest match %s\n", major, preset.name)
+		}
+
+		uconn := tlsutls.UClient(nil, &tlsutls.Config{InsecureSkipVerify: true}, preset.id)
+		if err := uconn.BuildHandshakeState(); err != nil {
+			fmt.Printf("Chrome %d: error building handshake state: %v\n", major, err)
+			continue
+		}
+		hello := uconn.HandshakeState.Hello
+		fillDeterministic(hello)
+
+		data, err := hello.Marshal()
+		if err != nil {
+			fmt.Printf("Chrome %d: error marshaling template: %v\n", major, err)
+			continue
+		}
+
+		filename := filepath.Join(templatesDir, fmt.Sprintf("chrome_%d.bin", major))
+		if err := os.WriteFile(filename, data, 0644); err != nil {
+			fmt.Printf("Chrome %d: error writing %s: %v\n", major, filename, err)
+			continue
+		}
+
+		fp, err := computeFingerprint(data, preset.name)
+		if err != nil {
+			fmt.Printf("Chrome %d: error fingerprinting template: %v\n", major, err)
+			continue
+		}
+
+		entry := catalogEntry{
+			ChromeVersion: version,
+			UTLSPreset:    preset.name,
+			TemplateFile:  filename,
+			JA3Hash:       fp.JA3Hash,
+			JA4Hash:       fp.JA4Hash,
+			GeneratedAt:   now,
+		}
+
+		if verifyRemote {
+			cipher, alpn, err := verifyHandshake(preset.id)
+			if err != nil {
+				fmt.Printf("verify-remote: %s: %v\n", preset.name, err)
+			} else {
+				entry.NegotiatedCipher = cipher
+				entry.NegotiatedALPN = alpn
+				fmt.Printf("verify-remote: %s negotiated cipher=%s alpn=%q\n", preset.name, cipher, alpn)
+			}
+		}
+
+		entries = append(entries, entry)
+		fmt.Printf("Chrome %s: %s -> %s (ja3=%s ja4=%s)\n", version, preset.name, filename, fp.JA3Hash, fp.JA4Hash)
+	}
+
+	catalogPath := filepath.Join(templatesDir, "catalog.json")
+	if err := writeJSON(catalogPath, entries); err != nil {
+		return fmt.Errorf("writing %s: %w", catalogPath, err)
+	}
+
+	fmt.Printf("Wrote %s with %d entries.\n", catalogPath, len(entries))
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// clientHello is the subset of a parsed TLS ClientHello needed to compute
+// JA3/JA4 fingerprints. It is parsed directly off the wire bytes produced by
+// (*utls.ClientHelloMsg).Marshal rather than read back off uTLS's internal
+// types, so the fingerprint reflects exactly what was (or will be) sent on
+// the wire.
+type clientHello struct {
+	version           uint16
+	supportedVersions []uint16
+	ciphers           []uint16
+	extensions        []uint16 // in wire order, GREASE included
+	curves            []uint16
+	pointFormats      []uint8
+	sigAlgs           []uint16
+	alpn              []string
+	sni               bool
+}
+
+// parseClientHello parses a raw ClientHello handshake message, optionally
+// prefixed by the 4-byte handshake header (1-byte type + 3-byte length) that
+// crypto/tls-style Marshal implementations include.
+func parseClientHello(data []byte) (*clientHello, error) {
+	if len(data) > 4 && data[0] == 0x01 {
+		bodyLen := int(data[1])<<16 | int(data[2])<<8 | int(data[3])
+		if bodyLen == len(data)-4 {
+			data = data[4:]
+		}
+	}
+
+	r := &byteReader{buf: data}
+	ch := &clientHello{}
+
+	ch.version = r.u16()
+	r.skip(32) // random
+	r.skip(int(r.u8())) // legacy session id
+
+	cipherBytes := r.bytes(int(r.u16()))
+	for i := 0; i+1 < len(cipherBytes); i += 2 {
+		ch.ciphers = append(ch.ciphers, binary.BigEndian.Uint16(cipherBytes[i:]))
+	}
+
+	r.skip(int(r.u8())) // compression methods
+
+	if r.remaining() == 0 {
+		return ch, r.err
+	}
+
+	extBytes := r.bytes(int(r.u16()))
+	er := &byteReader{buf: extBytes}
+	for er.remaining() > 0 && er.err == nil {
+		extType := er.u16()
+		extData := er.bytes(int(er.u16()))
+		ch.extensions = append(ch.extensions, extType)
+		parseExtension(ch, extType, extData)
+	}
+	if er.err != nil {
+		return nil, fmt.Errorf("parsing extensions: %w", er.err)
+	}
+	return ch, r.err
+}
+
+func parseExtension(ch *clientHello, extType uint16, data []byte) {
+	switch extType {
+	case 0x0000: // server_name
+		ch.sni = true
+	case 0x000a: // supported_groups (elliptic curves)
+		lr := &byteReader{buf: data}
+		list := lr.bytes(int(lr.u16()))
+		for i := 0; i+1 < len(list); i += 2 {
+			ch.curves = append(ch.curves, binary.BigEndian.Uint16(list[i:]))
+		}
+	case 0x000b: // ec_point_formats
+		lr := &byteReader{buf: data}
+		ch.pointFormats = append(ch.pointFormats, lr.bytes(int(lr.u8()))...)
+	case 0x000d: // signature_algorithms
+		lr := &byteReader{buf: data}
+		list := lr.bytes(int(lr.u16()))
+		for i := 0; i+1 < len(list); i += 2 {
+			ch.sigAlgs = append(ch.sigAlgs, binary.BigEndian.Uint16(list[i:]))
+		}
+	case 0x0010: // application_layer_protocol_negotiation
+		lr := &byteReader{buf: data}
+		list := lr.bytes(int(lr.u16()))
+		ar := &byteReader{buf: list}
+		for ar.remaining() > 0 {
+			ch.alpn = append(ch.alpn, string(ar.bytes(int(ar.u8()))))
+		}
+	case 0x002b: // supported_versions
+		lr := &byteReader{buf: data}
+		list := lr.bytes(int(lr.u8()))
+		for i := 0; i+1 < len(list); i += 2 {
+			ch.supportedVersions = append(ch.supportedVersions, binary.BigEndian.Uint16(list[i:]))
+		}
+	}
+}
+
+// effectiveVersion returns the highest version the ClientHello offers,
+// preferring supported_versions (TLS 1.3's real version signal) over the
+// legacy version field. Chrome's mandatory GREASE entry in
+// supported_versions (e.g. 0x9a9a) numerically outranks every real TLS
+// version, so it must be excluded before taking the max.
+func (c *clientHello) effectiveVersion() uint16 {
+	v := c.version
+	for _, sv := range withoutGREASE(c.supportedVersions) {
+		if sv > v {
+			v = sv
+		}
+	}
+	return v
+}
+
+// isGREASE reports whether v is one of the reserved GREASE values (RFC
+// 8701, 0x?a?a with both bytes equal) that JA4 excludes from its counts and
+// hashes.
+func isGREASE(v uint16) bool {
+	hi, lo := byte(v>>8), byte(v)
+	return hi == lo && hi&0x0f == 0x0a
+}
+
+type byteReader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func (r *byteReader) remaining() int { return len(r.buf) - r.pos }
+
+func (r *byteReader) u8() uint8 {
+	if r.err != nil || r.pos >= len(r.buf) {
+		r.err = fmt.Errorf("truncated ClientHello")
+		return 0
+	}
+	v := r.buf[r.pos]
+	r.pos++
+	return v
+}
+
+func (r *byteReader) u16() uint16 {
+	if r.err != nil || r.pos+2 > len(r.buf) {
+		r.err = fmt.Errorf("truncated ClientHello")
+		return 0
+	}
+	v := binary.BigEndian.Uint16(r.buf[r.pos:])
+	r.pos += 2
+	return v
+}
+
+func (r *byteReader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || r.pos+n > len(r.buf) {
+		r.err = fmt.Errorf("truncated ClientHello")
+		return nil
+	}
+	v := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return v
+}
+
+func (r *byteReader) skip(n int) { r.bytes(n) }
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fingerprint is the pinned identity of a generated ClientHello template.
+// selfTest recomputes this from the .bin file and fails if it drifts from
+// the sibling .json, which turns template generation into a regression gate
+// against silent uTLS/upstream changes.
+type fingerprint struct {
+	JA3            string `json:"ja3"`
+	JA3Hash        string `json:"ja3_hash"`
+	JA4            string `json:"ja4"`
+	JA4Hash        string `json:"ja4_hash"`
+	TemplateSHA256 string `json:"template_sha256"`
+	ChromeVersion  string `json:"chrome_version"`
+}
+
+// computeFingerprint derives JA3/JA4 and a digest of the raw template bytes
+// from a marshaled ClientHello.
+func computeFingerprint(raw []byte, presetName string) (*fingerprint, error) {
+	ch, err := parseClientHello(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing ClientHello for fingerprinting: %w", err)
+	}
+
+	ja3 := ja3String(ch)
+	ja4 := ja4String(ch)
+
+	sum := sha256.Sum256(raw)
+
+	return &fingerprint{
+		JA3:            ja3,
+		JA3Hash:        md5Hex(ja3),
+		JA4:            ja4,
+		JA4Hash:        sha256Hex(ja3 + "|" + ja4),
+		TemplateSHA256: hex.EncodeToString(sum[:]),
+		ChromeVersion:  presetName,
+	}, nil
+}
+
+// ja3String builds the classic JA3 fingerprint string:
+// SSLVersion,Ciphers,Extensions,EllipticCurves,EllipticCurvePointFormats
+// (dash-joined lists, comma-separated fields). Per the original JA3 spec,
+// GREASE values are left in place rather than filtered out.
+func ja3String(ch *clientHello) string {
+	return strings.Join([]string{
+		fmt.Sprintf("%d", ch.version),
+		joinU16(ch.ciphers),
+		joinU16(ch.extensions),
+		joinU16(ch.curves),
+		joinU8(ch.pointFormats),
+	}, ",")
+}
+
+// ja4String builds a JA4 fingerprint for a TLS-over-TCP client: a human
+// readable prefix (protocol, version, SNI, cipher/extension counts, ALPN)
+// followed by two truncated SHA-256 segments, one over the cipher list and
+// one over the extension + signature-algorithm lists. GREASE values are
+// excluded from every count and hash, per the JA4 spec.
+func ja4String(ch *clientHello) string {
+	ciphers := withoutGREASE(ch.ciphers)
+	extensions := withoutGREASE(ch.extensions)
+
+	alpn := "00"
+	if len(ch.alpn) > 0 && len(ch.alpn[0]) > 0 {
+		first := ch.alpn[0]
+		alpn = string(first[0]) + string(first[len(first)-1])
+	}
+
+	sni := "i"
+	if ch.sni {
+		sni = "d"
+	}
+
+	prefix := fmt.Sprintf("t%s%s%02d%02d%s",
+		ja4Version(ch.effectiveVersion()),
+		sni,
+		clamp99(len(ciphers)),
+		clamp99(len(extensions)),
+		alpn,
+	)
+
+	cipherHex := make([]string, len(ciphers))
+	for i, c := range ciphers {
+		cipherHex[i] = fmt.Sprintf("%04x", c)
+	}
+	sort.Strings(cipherHex)
+
+	extHex := make([]string, 0, len(extensions))
+	for _, e := range extensions {
+		if e == 0x0000 || e == 0x0010 { // SNI and ALPN are excluded from the extension hash
+			continue
+		}
+		extHex = append(extHex, fmt.Sprintf("%04x", e))
+	}
+	sort.Strings(extHex)
+
+	sigAlgHex := make([]string, len(ch.sigAlgs))
+	for i, s := range ch.sigAlgs {
+		sigAlgHex[i] = fmt.Sprintf("%04x", s)
+	}
+
+	ja4B := sha256Hex(strings.Join(cipherHex, ","))[:12]
+	ja4C := sha256Hex(strings.Join(extHex, ",") + "_" + strings.Join(sigAlgHex, ","))[:12]
+
+	return fmt.Sprintf("%s_%s_%s", prefix, ja4B, ja4C)
+}
+
+func ja4Version(v uint16) string {
+	switch v {
+	case 0x0304:
+		return "13"
+	case 0x0303:
+		return "12"
+	case 0x0302:
+		return "11"
+	case 0x0301:
+		return "10"
+	case 0x0300:
+		return "s3"
+	default:
+		return "00"
+	}
+}
+
+func withoutGREASE(vs []uint16) []uint16 {
+	out := make([]uint16, 0, len(vs))
+	for _, v := range vs {
+		if !isGREASE(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func clamp99(n int) int {
+	if n > 99 {
+		return 99
+	}
+	return n
+}
+
+func joinU16(vs []uint16) string {
+	parts := make([]string, len(vs))
+	for i, v := range vs {
+		parts[i] = fmt.Sprintf("%d", v)
+	}
+	return strings.Join(parts, "-")
+}
+
+func joinU8(vs []uint8) string {
+	parts := make([]string, len(vs))
+	for i, v := range vs {
+		parts[i] = fmt.Sprintf("%d", v)
+	}
+	return strings.Join(parts, "-")
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+// TestEffectiveVersionIgnoresGREASE guards against the GREASE entry Chrome
+// always places first in supported_versions (e.g. 0x9a9a = 39578)
+// numerically outranking the real TLS 1.3 value (0x0304 = 772) and being
+// reported as the ClientHello's effective version.
+func TestEffectiveVersionIgnoresGREASE(t *testing.T) {
+	ch := &clientHello{
+		version:           0x0303,
+		supportedVersions: []uint16{0x9a9a, 0x0304, 0x0303},
+	}
+	if got := ch.effectiveVersion(); got != 0x0304 {
+		t.Fatalf("effectiveVersion() = 0x%04x, want 0x0304", got)
+	}
+}
+
+func TestJA4VersionWithGREASE(t *testing.T) {
+	ch := &clientHello{
+		version:           0x0303,
+		supportedVersions: []uint16{0x9a9a, 0x0304, 0x0303},
+	}
+	if got := ja4Version(ch.effectiveVersion()); got != "13" {
+		t.Fatalf("ja4Version(effectiveVersion()) = %q, want \"13\"", got)
+	}
+}
+
+func TestJA4StringExcludesGREASEFromCounts(t *testing.T) {
+	ch := &clientHello{
+		version:           0x0303,
+		supportedVersions: []uint16{0x9a9a, 0x0304, 0x0303},
+		ciphers:           []uint16{0x0a0a, 0x1301, 0x1302},
+		extensions:        []uint16{0x0a0a, 0x0000, 0x002b},
+	}
+	ja4 := ja4String(ch)
+	want := "t13i0202"
+	if len(ja4) < len(want) || ja4[:len(want)] != want {
+		t.Fatalf("ja4String() = %q, want prefix %q (version 13, 2 real ciphers, 2 real extensions)", ja4, want)
+	}
+}
@@ -3,8 +3,8 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
+	"strings"
 
 	tlsutls "github.com/refraction-networking/utls"
 	"github.com/spf13/cobra"
@@ -24,14 +24,24 @@ var generateCmd = &cobra.Command{
 	},
 }
 
+var (
+	updateMilestones   int
+	updateVerifyRemote bool
+)
+
 var updateCmd = &cobra.Command{
 	Use:   "update",
-	Short: "Update templates from latest Chrome releases",
+	Short: "Update the template catalog from real Chrome Stable releases",
 	Run: func(cmd *cobra.Command, args []string) {
 		updateTemplates()
 	},
 }
 
+func init() {
+	updateCmd.Flags().IntVar(&updateMilestones, "milestones", 3, "number of latest Chrome Stable milestones to track")
+	updateCmd.Flags().BoolVar(&updateVerifyRemote, "verify-remote", false, "handshake against www.google.com with each preset and record what it negotiates")
+}
+
 var selfTestCmd = &cobra.Command{
 	Use:   "self-test",
 	Short: "Run self-test on generated templates",
@@ -61,15 +71,15 @@ func generateTemplates() {
 	}
 
 	for i, id := range ids {
-		// Create a uTLS connection to get the ClientHello
+		// Create a uTLS connection and build its handshake state so the
+		// preset's ciphers/extensions are actually applied to Hello.
 		uconn := tlsutls.UClient(nil, &tlsutls.Config{InsecureSkipVerify: true}, id)
+		if err := uconn.BuildHandshakeState(); err != nil {
+			fmt.Printf("Error building handshake state for template %d: %v\n", i, err)
+			continue
+		}
 		hello := uconn.HandshakeState.Hello
-
-		// Make deterministic
-		hello.Random = make([]byte, 32)
-		hello.SessionId = make([]byte, 32)
-		copy(hello.Random, []byte("qnet-deterministic-random-1234567"))
-		copy(hello.SessionId, []byte("qnet-session-12345678901234567"))
+		fillDeterministic(hello)
 
 		data, err := hello.Marshal()
 		if err != nil {
@@ -78,51 +88,44 @@ func generateTemplates() {
 		}
 
 		filename := fmt.Sprintf("template_%d.bin", i)
-		err = os.WriteFile(filename, data, 0644)
-		if err != nil {
+		if err := os.WriteFile(filename, data, 0644); err != nil {
 			fmt.Printf("Error writing %s: %v\n", filename, err)
 			continue
 		}
-		fmt.Printf("Generated %s for %s\n", filename, id.Str())
+
+		fp, err := computeFingerprint(data, id.Str())
+		if err != nil {
+			fmt.Printf("Error fingerprinting template %d: %v\n", i, err)
+			continue
+		}
+		jsonFilename := fmt.Sprintf("template_%d.json", i)
+		if err := writeJSON(jsonFilename, fp); err != nil {
+			fmt.Printf("Error writing %s: %v\n", jsonFilename, err)
+			continue
+		}
+
+		fmt.Printf("Generated %s and %s for %s (ja3=%s ja4=%s)\n", filename, jsonFilename, id.Str(), fp.JA3Hash, fp.JA4)
 	}
 
 	fmt.Println("Templates generated successfully.")
 }
 
 func updateTemplates() {
-	fmt.Println("Updating templates from latest Chrome releases...")
-
-	// Fetch latest Chrome version from GitHub API
-	resp, err := http.Get("https://api.github.com/repos/chromium/chromium/releases/latest")
-	if err != nil {
-		fmt.Printf("Error fetching Chrome releases: %v\n", err)
-		return
-	}
-	defer resp.Body.Close()
+	fmt.Printf("Updating template catalog for the %d latest Chrome Stable milestones...\n", updateMilestones)
 
-	var release struct {
-		TagName string `json:"tag_name"`
-	}
-	err = json.NewDecoder(resp.Body).Decode(&release)
-	if err != nil {
-		fmt.Printf("Error parsing release: %v\n", err)
-		return
+	if err := buildCatalog(updateMilestones, updateVerifyRemote); err != nil {
+		fmt.Printf("Error updating template catalog: %v\n", err)
+		os.Exit(1)
 	}
-
-	fmt.Printf("Latest Chrome version: %s\n", release.TagName)
-
-	// For now, just regenerate with updated version info
-	generateTemplates()
 }
 
 func selfTest() {
 	fmt.Println("Running self-test...")
 
-	// Check if templates exist
 	files, err := os.ReadDir(".")
 	if err != nil {
 		fmt.Printf("Error reading directory: %v\n", err)
-		return
+		os.Exit(1)
 	}
 
 	templateCount := 0
@@ -130,30 +133,84 @@ func selfTest() {
 		if file.IsDir() {
 			continue
 		}
-		if len(file.Name()) > 9 && file.Name()[:9] == "template_" {
+		if strings.HasSuffix(file.Name(), ".bin") && strings.HasPrefix(file.Name(), "template_") {
 			templateCount++
 		}
 	}
 
 	if templateCount == 0 {
 		fmt.Println("No templates found. Run 'generate' first.")
-		return
+		os.Exit(1)
 	}
 
 	fmt.Printf("Found %d templates.\n", templateCount)
 
-	// Test parsing
+	drifted := false
 	for i := 0; i < templateCount; i++ {
-		filename := fmt.Sprintf("template_%d.bin", i)
-		data, err := os.ReadFile(filename)
+		binFilename := fmt.Sprintf("template_%d.bin", i)
+		jsonFilename := fmt.Sprintf("template_%d.json", i)
+
+		data, err := os.ReadFile(binFilename)
+		if err != nil {
+			fmt.Printf("Error reading %s: %v\n", binFilename, err)
+			drifted = true
+			continue
+		}
+
+		var pinned fingerprint
+		pinnedRaw, err := os.ReadFile(jsonFilename)
+		if err != nil {
+			fmt.Printf("Template %d: no pinned %s to compare against\n", i, jsonFilename)
+			drifted = true
+			continue
+		}
+		if err := json.Unmarshal(pinnedRaw, &pinned); err != nil {
+			fmt.Printf("Template %d: error parsing %s: %v\n", i, jsonFilename, err)
+			drifted = true
+			continue
+		}
+
+		got, err := computeFingerprint(data, pinned.ChromeVersion)
 		if err != nil {
-			fmt.Printf("Error reading %s: %v\n", filename, err)
+			fmt.Printf("Template %d: error re-parsing %s: %v\n", i, binFilename, err)
+			drifted = true
 			continue
 		}
 
-		// For self-test, just check file sizes or something simple
-		fmt.Printf("Template %d: file size %d bytes\n", i, len(data))
+		if *got != pinned {
+			fmt.Printf("Template %d: FINGERPRINT DRIFT in %s\n", i, binFilename)
+			fmt.Printf("  pinned: ja3=%s ja4=%s template_sha256=%s\n", pinned.JA3Hash, pinned.JA4, pinned.TemplateSHA256)
+			fmt.Printf("  got:    ja3=%s ja4=%s template_sha256=%s\n", got.JA3Hash, got.JA4, got.TemplateSHA256)
+			drifted = true
+			continue
+		}
+
+		fmt.Printf("Template %d: OK (ja3=%s ja4=%s)\n", i, got.JA3Hash, got.JA4)
+	}
+
+	if drifted {
+		fmt.Println("Self-test FAILED: one or more templates drifted from their pinned fingerprint.")
+		os.Exit(1)
 	}
 
 	fmt.Println("Self-test passed!")
 }
+
+// fillDeterministic overwrites the Random and SessionId fields uTLS would
+// otherwise fill with crypto/rand, so repeated runs of `generate` and
+// `update` produce byte-identical templates instead of fresh random ones
+// every time.
+func fillDeterministic(hello *tlsutls.PubClientHelloMsg) {
+	hello.Random = make([]byte, 32)
+	hello.SessionId = make([]byte, 32)
+	copy(hello.Random, []byte("qnet-deterministic-random-1234567"))
+	copy(hello.SessionId, []byte("qnet-session-12345678901234567"))
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}